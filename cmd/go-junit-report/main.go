@@ -0,0 +1,173 @@
+// Command go-junit-report reads go test output and converts it into one of
+// several test report formats.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jstemmer/go-junit-report/v2/pkg/gtr"
+	"github.com/jstemmer/go-junit-report/v2/pkg/junit/merge"
+	"github.com/jstemmer/go-junit-report/v2/pkg/parser/gojson"
+	"github.com/jstemmer/go-junit-report/v2/pkg/report"
+)
+
+var (
+	format         = flag.String("format", "junit", "report format to produce: junit, json, bazel, ginkgo")
+	rerunFailCount = flag.Int("rerun-fail-count", 0, "rerun each failed test up to this many times and mark it flaky if it ever passes")
+	rerunPackage   = flag.String("rerun-package", "./...", "package pattern to pass to `go test` when rerunning failed tests")
+	iocopy         = flag.Bool("iocopy", false, "tee raw test output to stdout and emit JUnit XML incrementally, one package at a time")
+	outPath        = flag.String("out", "report.xml", "output path for the incremental JUnit XML file written in -iocopy mode")
+	benchBaseline  = flag.String("bench-baseline", "", "path to a baseline JSON report (see -format=json) to compare benchmarks against")
+	benchFail      = flag.String("benchfail", "", "mark benchmarks that regressed by more than this percentage (e.g. \"10%\") as failed; requires -bench-baseline")
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "merge" {
+		if err := runMerge(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "go-junit-report:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	flag.Parse()
+
+	if *iocopy {
+		if err := runStreaming(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "go-junit-report:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := run(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "go-junit-report:", err)
+		os.Exit(1)
+	}
+}
+
+// runStreaming feeds events from in into a gtr.StreamingBuilder as they
+// arrive, one JSON-encoded gtr.Event per line, tee-ing raw output to tee
+// and writing the JUnit report incrementally to *out.
+func runStreaming(in io.Reader, tee io.Writer) error {
+	builder := gtr.NewStreamingBuilder(tee)
+	builder.SetOutputPath(*outPath)
+
+	dec := json.NewDecoder(in)
+	for dec.More() {
+		var ev gtr.Event
+		if err := dec.Decode(&ev); err != nil {
+			return fmt.Errorf("reading event: %w", err)
+		}
+		if err := builder.Feed(ev); err != nil {
+			return err
+		}
+	}
+
+	return builder.Close()
+}
+
+// runMerge implements the `go-junit-report merge <file>...` subcommand: it
+// reads each given JUnit XML file and writes one consolidated report to out.
+func runMerge(paths []string, out io.Writer) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("merge: no input files given")
+	}
+
+	suites, err := merge.Files(paths)
+	if err != nil {
+		return err
+	}
+
+	return suites.WriteXML(out)
+}
+
+func run(in io.Reader, out io.Writer) error {
+	var events []gtr.Event
+	if err := json.NewDecoder(in).Decode(&events); err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+
+	writer, ok := report.NewWriter(report.Format(*format), hostname(), time.Now())
+	if !ok {
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	rep := gtr.FromEvents(events, "")
+	if *rerunFailCount > 0 {
+		rep = gtr.RerunFailed(rep, *rerunFailCount, goTestRunner(*rerunPackage))
+	}
+	if *benchBaseline != "" {
+		var err error
+		rep, err = applyBenchFail(rep, *benchBaseline, *benchFail)
+		if err != nil {
+			return err
+		}
+	}
+	return writer.Write(out, rep)
+}
+
+// applyBenchFail loads the baseline report from baselinePath and, if
+// threshold is set (e.g. "10%"), marks every benchmark in rep that
+// regressed by more than that percentage as failed.
+func applyBenchFail(rep gtr.Report, baselinePath, threshold string) (gtr.Report, error) {
+	data, err := os.ReadFile(baselinePath)
+	if err != nil {
+		return rep, fmt.Errorf("reading bench baseline: %w", err)
+	}
+
+	var baseline gtr.Report
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return rep, fmt.Errorf("parsing bench baseline: %w", err)
+	}
+
+	if threshold == "" {
+		return rep, nil
+	}
+
+	pct, err := strconv.ParseFloat(strings.TrimSuffix(threshold, "%"), 64)
+	if err != nil {
+		return rep, fmt.Errorf("invalid -benchfail threshold %q: %w", threshold, err)
+	}
+
+	return gtr.MarkBenchmarkRegressions(rep, baseline, pct), nil
+}
+
+// goTestRunner returns a gtr.TestRunner that invokes `go test -json
+// -run=<pattern> pkg` for a single test and parses its test2json output
+// into gtr.Events with the gojson parser, rather than decoding it as the
+// tool's own internal event encoding.
+func goTestRunner(pkg string) gtr.TestRunner {
+	return func(testName string) ([]gtr.Event, error) {
+		cmd := exec.Command("go", "test", "-json", "-run="+gtr.TestRunArg(testName), pkg)
+		stdout, err := cmd.Output()
+		if err != nil {
+			if _, ok := err.(*exec.ExitError); !ok {
+				return nil, err
+			}
+		}
+
+		events, err := gojson.Parse(bytes.NewReader(stdout))
+		if err != nil {
+			return nil, fmt.Errorf("parsing rerun output for %s: %w", testName, err)
+		}
+		return events, nil
+	}
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,301 @@
+package gtr
+
+import "math"
+
+// BenchmarkStats holds the mean, standard deviation, minimum, and maximum
+// of one metric (e.g. ns/op) across a benchmark's samples.
+type BenchmarkStats struct {
+	Mean   float64
+	Stddev float64
+	Min    float64
+	Max    float64
+	N      int
+}
+
+// BenchmarkMetrics collects the BenchmarkStats for each of the metrics
+// go test reports for a benchmark.
+type BenchmarkMetrics struct {
+	NsPerOp     BenchmarkStats
+	MBPerSec    BenchmarkStats
+	BytesPerOp  BenchmarkStats
+	AllocsPerOp BenchmarkStats
+}
+
+// Stats computes mean, standard deviation, min, and max for each metric
+// across bm's samples.
+func (bm Benchmark) Stats() BenchmarkMetrics {
+	n := len(bm.Samples)
+	ns := make([]float64, n)
+	mb := make([]float64, n)
+	bytes := make([]float64, n)
+	allocs := make([]float64, n)
+	for i, s := range bm.Samples {
+		ns[i] = s.NsPerOp
+		mb[i] = s.MBPerSec
+		bytes[i] = float64(s.BytesPerOp)
+		allocs[i] = float64(s.AllocsPerOp)
+	}
+
+	return BenchmarkMetrics{
+		NsPerOp:     computeStats(ns),
+		MBPerSec:    computeStats(mb),
+		BytesPerOp:  computeStats(bytes),
+		AllocsPerOp: computeStats(allocs),
+	}
+}
+
+func computeStats(samples []float64) BenchmarkStats {
+	n := len(samples)
+	if n == 0 {
+		return BenchmarkStats{}
+	}
+
+	stats := BenchmarkStats{N: n, Min: samples[0], Max: samples[0]}
+	var sum float64
+	for _, v := range samples {
+		sum += v
+		if v < stats.Min {
+			stats.Min = v
+		}
+		if v > stats.Max {
+			stats.Max = v
+		}
+	}
+	stats.Mean = sum / float64(n)
+
+	if n > 1 {
+		var sumSq float64
+		for _, v := range samples {
+			d := v - stats.Mean
+			sumSq += d * d
+		}
+		stats.Stddev = math.Sqrt(sumSq / float64(n-1))
+	}
+
+	return stats
+}
+
+// BenchmarkComparison is the result of comparing one benchmark between two
+// reports, in the style of benchstat.
+type BenchmarkComparison struct {
+	Name string
+
+	Old BenchmarkStats
+	New BenchmarkStats
+
+	// DeltaPct is the percent change of New.Mean relative to Old.Mean.
+	DeltaPct float64
+
+	// PValue is the two-sided p-value of a Welch's t-test between the old
+	// and new samples. A small p-value means the change is unlikely to be
+	// due to chance alone.
+	PValue float64
+}
+
+// CompareBenchmarks compares every benchmark present in both old and new,
+// computing the percent delta of their means (using NsPerOp) and a
+// Welch's t-test p-value for statistical significance.
+func CompareBenchmarks(old, new Report) []BenchmarkComparison {
+	oldBenchmarks := indexBenchmarks(old)
+	newBenchmarks := indexBenchmarks(new)
+
+	var comparisons []BenchmarkComparison
+	for name, oldBm := range oldBenchmarks {
+		newBm, ok := newBenchmarks[name]
+		if !ok {
+			continue
+		}
+
+		oldStats := oldBm.Stats().NsPerOp
+		newStats := newBm.Stats().NsPerOp
+
+		comparisons = append(comparisons, BenchmarkComparison{
+			Name:     name,
+			Old:      oldStats,
+			New:      newStats,
+			DeltaPct: percentDelta(oldStats.Mean, newStats.Mean),
+			PValue:   welchTTest(oldStats, newStats),
+		})
+	}
+
+	return comparisons
+}
+
+// significanceLevel is the p-value threshold below which a benchmark's
+// delta is considered statistically significant rather than noise, the
+// same default benchstat itself uses.
+const significanceLevel = 0.05
+
+// MarkBenchmarkRegressions compares report against baseline and marks the
+// Result of every benchmark in report whose mean ns/op regressed by more
+// than thresholdPct as Fail, so the JUnit writer emits it as a <failure>.
+// A large delta alone isn't enough: the Welch's t-test p-value must also
+// be below significanceLevel, otherwise a noisy benchmark with too much
+// run-to-run variance would fail on every run regardless of threshold,
+// which is exactly what the t-test exists to rule out.
+func MarkBenchmarkRegressions(report, baseline Report, thresholdPct float64) Report {
+	comparisons := make(map[string]BenchmarkComparison)
+	for _, c := range CompareBenchmarks(baseline, report) {
+		comparisons[c.Name] = c
+	}
+
+	for pi := range report.Packages {
+		benchmarks := report.Packages[pi].Benchmarks
+		for bi := range benchmarks {
+			c, ok := comparisons[benchmarks[bi].Name]
+			if ok && c.DeltaPct > thresholdPct && c.PValue < significanceLevel {
+				benchmarks[bi].Result = Fail
+			}
+		}
+	}
+
+	return report
+}
+
+// indexBenchmarks builds a name -> Benchmark index for report, one entry
+// per distinct benchmark, for comparison by CompareBenchmarks. A Benchmark
+// that already carries Samples (e.g. one loaded from a persisted JSON
+// report, or already merged) is indexed as-is; only Benchmarks without
+// Samples are treated as raw per-run entries and folded together with
+// mergeBenchmarks, which rebuilds Samples from each one's single-run
+// fields. Running an already-sampled Benchmark back through mergeBenchmarks
+// would discard its real Samples and replace them with one bogus entry
+// built from its (zero-valued) raw fields.
+func indexBenchmarks(report Report) map[string]Benchmark {
+	index := make(map[string]Benchmark)
+	for _, pkg := range report.Packages {
+		var raw []Benchmark
+		for _, bm := range pkg.Benchmarks {
+			if len(bm.Samples) > 0 {
+				index[bm.Name] = bm
+				continue
+			}
+			raw = append(raw, bm)
+		}
+		for _, bm := range mergeBenchmarks(raw) {
+			index[bm.Name] = bm
+		}
+	}
+	return index
+}
+
+func percentDelta(old, new float64) float64 {
+	if old == 0 {
+		return 0
+	}
+	return (new - old) / old * 100
+}
+
+// welchTTest computes the two-sided p-value of Welch's t-test between two
+// sets of summary statistics: t = (m1-m2) / sqrt(s1^2/n1 + s2^2/n2), with
+// degrees of freedom from the Welch-Satterthwaite equation.
+func welchTTest(a, b BenchmarkStats) float64 {
+	if a.N < 2 || b.N < 2 {
+		return 1
+	}
+
+	va := a.Stddev * a.Stddev / float64(a.N)
+	vb := b.Stddev * b.Stddev / float64(b.N)
+	if va+vb == 0 {
+		return 1
+	}
+
+	t := (a.Mean - b.Mean) / math.Sqrt(va+vb)
+
+	df := (va + vb) * (va + vb) /
+		(va*va/float64(a.N-1) + vb*vb/float64(b.N-1))
+
+	return studentTTwoSided(t, df)
+}
+
+// studentTTwoSided computes the two-sided p-value for Student's
+// t-distribution with df degrees of freedom, via the regularized
+// incomplete beta function.
+func studentTTwoSided(t, df float64) float64 {
+	x := df / (df + t*t)
+	return incompleteBeta(x, df/2, 0.5)
+}
+
+// incompleteBeta evaluates the regularized incomplete beta function I_x(a,
+// b) using a continued-fraction expansion (Numerical Recipes style),
+// which is sufficient precision for the small sample counts involved in
+// comparing benchmark runs.
+func incompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lnBeta := lgamma(a+b) - lgamma(a) - lgamma(b) +
+		a*math.Log(x) + b*math.Log(1-x)
+	front := math.Exp(lnBeta)
+
+	if x < (a+1)/(a+b+2) {
+		return front * betaCF(x, a, b) / a
+	}
+	return 1 - front*betaCF(1-x, b, a)/b
+}
+
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
+
+// betaCF evaluates the continued fraction for the incomplete beta function
+// using the modified Lentz's method.
+func betaCF(x, a, b float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 3e-10
+		tiny          = 1e-30
+	)
+
+	qab := a + b
+	qap := a + 1
+	qam := a - 1
+
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIterations; m++ {
+		m2 := float64(2 * m)
+
+		aa := float64(m) * (b - float64(m)) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		aa = -(a + float64(m)) * (qab + float64(m)) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+
+		if math.Abs(del-1) < epsilon {
+			break
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,50 @@
+package gtr
+
+import "testing"
+
+func benchmarkWithSamples(name string, samples ...float64) Benchmark {
+	bmSamples := make([]BenchmarkSample, len(samples))
+	for i, s := range samples {
+		bmSamples[i] = BenchmarkSample{NsPerOp: s}
+	}
+	return Benchmark{Name: name, Samples: bmSamples}
+}
+
+func reportWithBenchmark(bm Benchmark) Report {
+	return Report{Packages: []Package{{Benchmarks: []Benchmark{bm}}}}
+}
+
+func TestMarkBenchmarkRegressions_IgnoresInsignificantDelta(t *testing.T) {
+	baseline := reportWithBenchmark(benchmarkWithSamples("BenchmarkFoo", 100, 101, 99, 100, 102))
+	report := reportWithBenchmark(benchmarkWithSamples("BenchmarkFoo", 105, 95, 110, 90, 108))
+
+	got := MarkBenchmarkRegressions(report, baseline, 1)
+
+	if got.Packages[0].Benchmarks[0].Result == Fail {
+		t.Errorf("noisy, statistically insignificant delta was marked Fail")
+	}
+}
+
+func TestMarkBenchmarkRegressions_FlagsSignificantRegression(t *testing.T) {
+	baseline := reportWithBenchmark(benchmarkWithSamples("BenchmarkFoo", 99, 100, 101, 100, 100))
+	report := reportWithBenchmark(benchmarkWithSamples("BenchmarkFoo", 199, 200, 201, 200, 200))
+
+	got := MarkBenchmarkRegressions(report, baseline, 10)
+
+	if got.Packages[0].Benchmarks[0].Result != Fail {
+		t.Errorf("clear, consistent regression was not marked Fail")
+	}
+}
+
+func TestCompareBenchmarks_DeltaPct(t *testing.T) {
+	baseline := reportWithBenchmark(benchmarkWithSamples("BenchmarkFoo", 100, 100))
+	report := reportWithBenchmark(benchmarkWithSamples("BenchmarkFoo", 150, 150))
+
+	comparisons := CompareBenchmarks(baseline, report)
+	if len(comparisons) != 1 {
+		t.Fatalf("got %d comparisons, want 1", len(comparisons))
+	}
+	if comparisons[0].DeltaPct != 50 {
+		t.Errorf("DeltaPct = %v, want 50", comparisons[0].DeltaPct)
+	}
+}
@@ -0,0 +1,223 @@
+package gtr
+
+import (
+	"strings"
+	"time"
+)
+
+// Result represents the result of a test or benchmark.
+type Result int
+
+const (
+	Unknown Result = iota
+	Pass
+	Fail
+	Skip
+)
+
+// rootOutputLine is a line of output that arrived while no test was
+// actively running, paired with the test that was active immediately
+// before it went idle, if any.
+type rootOutputLine struct {
+	line       string
+	lastActive int // id of the test active just before this line arrived, or -1
+}
+
+// ReportBuilder helps build reports by tracking the currently active tests
+// and packages as they are encountered. It is used by FromEvents to turn a
+// stream of events into a Report.
+type ReportBuilder struct {
+	packageName string
+	output      []string
+
+	tests      []Test
+	testIDs    map[string]int // test name -> index into tests
+	current    int            // id of the test currently printing output, or -1
+	lastActive int            // id of the most recently active test, or -1
+	rootOutput []rootOutputLine
+
+	benchmarks []Benchmark
+
+	packages []Package
+
+	buildErr Error
+	runErr   Error
+
+	coveragePct float64
+}
+
+// NewReportBuilder creates a new ReportBuilder for the package with the
+// given name.
+func NewReportBuilder(packageName string) *ReportBuilder {
+	return &ReportBuilder{
+		packageName: packageName,
+		testIDs:     make(map[string]int),
+		current:     -1,
+		lastActive:  -1,
+	}
+}
+
+// CreateTest registers the start of a test with the given name and makes it
+// the currently active test. Since a subtest only starts once its parent's
+// own body has returned control to the test runner, creating it takes over
+// as the current test rather than stacking on top of the parent — so
+// output produced while a subtest runs is never misattributed back to an
+// still-"active" parent.
+func (b *ReportBuilder) CreateTest(name string) {
+	if _, ok := b.testIDs[name]; ok {
+		return
+	}
+	id := len(b.tests)
+	b.testIDs[name] = id
+	b.tests = append(b.tests, Test{
+		ID:    id,
+		Name:  name,
+		Level: strings.Count(name, "/"),
+	})
+	b.current = id
+	b.lastActive = id
+}
+
+// PauseTest marks the test with the given name as paused. If it is the
+// test currently receiving output, subsequent output is buffered as root
+// output (rather than falling back to whatever test was active before it)
+// until it, or another test, resumes with ContinueTest.
+func (b *ReportBuilder) PauseTest(name string) {
+	id, ok := b.testIDs[name]
+	if !ok || b.current != id {
+		return
+	}
+	b.current = -1
+	b.lastActive = id
+}
+
+// ContinueTest marks the test with the given name as active again, so that
+// subsequent output is attributed to it.
+func (b *ReportBuilder) ContinueTest(name string) {
+	id, ok := b.testIDs[name]
+	if !ok {
+		return
+	}
+	b.current = id
+	b.lastActive = id
+}
+
+// EndTest records the final result of the test with the given name,
+// flushing any buffered root output lines that belong to it.
+func (b *ReportBuilder) EndTest(name string, result Result, duration time.Duration, indent int) {
+	id, ok := b.testIDs[name]
+	if !ok {
+		b.CreateTest(name)
+		id = b.testIDs[name]
+	}
+
+	b.flushRootOutput(name, id)
+
+	if b.current == id {
+		b.current = -1
+	}
+
+	t := &b.tests[id]
+	t.Result = result
+	t.Duration = duration
+	t.Level = indent
+}
+
+// flushRootOutput moves any buffered root-level output lines that belong to
+// the given subtest into that subtest's own output, recovering output that
+// test2json attributed to no test at all (e.g. lines emitted by t.Cleanup
+// or after the subtest's goroutine has already returned) rather than the
+// subtest that actually produced it. A line is attributed to id either
+// because it was buffered right after id itself went idle, or — as a
+// fallback for genuinely test-less output — because its indent matches
+// id's nesting depth.
+func (b *ReportBuilder) flushRootOutput(name string, id int) {
+	if len(b.rootOutput) == 0 {
+		return
+	}
+
+	prefix := strings.Repeat("    ", strings.Count(name, "/")+1)
+
+	var remaining []rootOutputLine
+	for _, l := range b.rootOutput {
+		if l.lastActive == id || (l.lastActive == -1 && strings.HasPrefix(l.line, prefix)) {
+			b.tests[id].Output = append(b.tests[id].Output, l.line)
+			continue
+		}
+		remaining = append(remaining, l)
+	}
+	b.rootOutput = remaining
+}
+
+// AppendOutput appends the given line of output to the currently active
+// test, if any, or buffers it as root output — tagged with whichever test
+// was active immediately before going idle — so it can be reattributed
+// once that specific test ends, rather than to whichever sibling happens
+// to end first.
+func (b *ReportBuilder) AppendOutput(line string) {
+	if b.current != -1 {
+		b.tests[b.current].Output = append(b.tests[b.current].Output, line)
+		b.output = append(b.output, line)
+		return
+	}
+	b.rootOutput = append(b.rootOutput, rootOutputLine{line: line, lastActive: b.lastActive})
+	b.output = append(b.output, line)
+}
+
+// Benchmark records a completed benchmark run.
+func (b *ReportBuilder) Benchmark(name string, iterations int64, nsPerOp, mbPerSec float64, bytesPerOp, allocsPerOp int64) {
+	b.benchmarks = append(b.benchmarks, Benchmark{
+		Name:        name,
+		Result:      Pass,
+		Iterations:  iterations,
+		NsPerOp:     nsPerOp,
+		MBPerSec:    mbPerSec,
+		BytesPerOp:  bytesPerOp,
+		AllocsPerOp: allocsPerOp,
+	})
+}
+
+// Coverage records the package's statement coverage percentage.
+func (b *ReportBuilder) Coverage(pct float64, packages string) {
+	b.coveragePct = pct
+}
+
+// CreateBuildError records a build error for the package.
+func (b *ReportBuilder) CreateBuildError(name string) {
+	b.buildErr = Error{Name: name, Output: b.output}
+}
+
+// End finalizes any remaining bookkeeping for the current package.
+func (b *ReportBuilder) End() {}
+
+// CreatePackage finalizes the current package with the given summary
+// result and appends it to the list of packages tracked by this builder.
+func (b *ReportBuilder) CreatePackage(name string, result Result, duration time.Duration, data string) {
+	b.packages = append(b.packages, Package{
+		Name:       name,
+		Duration:   duration,
+		Coverage:   b.coveragePct,
+		Output:     b.output,
+		Tests:      b.tests,
+		Benchmarks: b.benchmarks,
+		BuildError: b.buildErr,
+		RunError:   b.runErr,
+	})
+
+	b.packageName = ""
+	b.output = nil
+	b.tests = nil
+	b.testIDs = make(map[string]int)
+	b.current = -1
+	b.lastActive = -1
+	b.rootOutput = nil
+	b.benchmarks = nil
+	b.buildErr = Error{}
+	b.runErr = Error{}
+	b.coveragePct = 0
+}
+
+// Build returns the Report constructed from the packages collected so far.
+func (b *ReportBuilder) Build() Report {
+	return Report{Packages: b.packages}
+}
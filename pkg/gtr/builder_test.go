@@ -0,0 +1,85 @@
+package gtr
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestReportBuilder_SubtestOutputNotAttributedToParent reproduces the
+// scenario where two subtests run in parallel: the parent's own body has
+// already returned, so output each subtest prints after it resumes must
+// not be attributed back to the parent, and must end up on that subtest.
+func TestReportBuilder_SubtestOutputNotAttributedToParent(t *testing.T) {
+	b := NewReportBuilder("pkg")
+
+	b.CreateTest("TestParent")
+	b.CreateTest("TestParent/A")
+	b.PauseTest("TestParent/A")
+	b.CreateTest("TestParent/B")
+	b.PauseTest("TestParent/B")
+
+	b.ContinueTest("TestParent/A")
+	b.AppendOutput("        a's output")
+	b.EndTest("TestParent/A", Pass, 0, 1)
+
+	b.ContinueTest("TestParent/B")
+	b.AppendOutput("        b's output")
+	b.EndTest("TestParent/B", Pass, 0, 1)
+
+	b.EndTest("TestParent", Pass, 0, 0)
+	b.CreatePackage("pkg", Pass, 0, "")
+
+	report := b.Build()
+	tests := report.Packages[0].Tests
+
+	got := map[string][]string{}
+	for _, test := range tests {
+		got[test.Name] = test.Output
+	}
+
+	if want := []string{"        a's output"}; !reflect.DeepEqual(got["TestParent/A"], want) {
+		t.Errorf("TestParent/A.Output = %v, want %v", got["TestParent/A"], want)
+	}
+	if want := []string{"        b's output"}; !reflect.DeepEqual(got["TestParent/B"], want) {
+		t.Errorf("TestParent/B.Output = %v, want %v", got["TestParent/B"], want)
+	}
+	if len(got["TestParent"]) != 0 {
+		t.Errorf("TestParent.Output = %v, want empty", got["TestParent"])
+	}
+}
+
+// TestReportBuilder_BufferedOutputRoutedToCorrectSibling reproduces output
+// that arrives while no test is current (e.g. during the window between
+// two parallel subtests pausing and resuming) and checks it's attributed
+// to the specific sibling that was running immediately beforehand, not to
+// whichever sibling happens to end first.
+func TestReportBuilder_BufferedOutputRoutedToCorrectSibling(t *testing.T) {
+	b := NewReportBuilder("pkg")
+
+	b.CreateTest("TestParent")
+	b.CreateTest("TestParent/A")
+	b.PauseTest("TestParent/A")
+	b.AppendOutput("        stray line from A's goroutine")
+
+	b.CreateTest("TestParent/B")
+	b.PauseTest("TestParent/B")
+
+	b.EndTest("TestParent/B", Pass, 0, 1)
+	b.EndTest("TestParent/A", Pass, 0, 1)
+	b.EndTest("TestParent", Pass, 0, 0)
+	b.CreatePackage("pkg", Pass, 0, "")
+
+	report := b.Build()
+	for _, test := range report.Packages[0].Tests {
+		switch test.Name {
+		case "TestParent/A":
+			if want := []string{"        stray line from A's goroutine"}; !reflect.DeepEqual(test.Output, want) {
+				t.Errorf("TestParent/A.Output = %v, want %v", test.Output, want)
+			}
+		case "TestParent/B":
+			if len(test.Output) != 0 {
+				t.Errorf("TestParent/B.Output = %v, want empty", test.Output)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package gtr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BuildDiagnostic is a single compiler diagnostic at a specific file:line,
+// as parsed from Go's build output by ParseBuildErrors.
+type BuildDiagnostic struct {
+	File    string
+	Line    int
+	Col     int
+	Message string
+}
+
+var (
+	buildDiagnosticRe = regexp.MustCompile(`^(\S+\.go):(\d+):(?:(\d+):)?\s*(.*)$`)
+	packageBannerRe   = regexp.MustCompile(`^# \S+`)
+)
+
+// ParseBuildErrors recognizes the standard Go compiler diagnostic format
+// "file.go:line:col: message" (including multi-line messages continued on
+// indented lines, and "# package" banners that separate one package's
+// output from the next) and returns one BuildDiagnostic per distinct
+// file:line site, in the order they were first seen.
+func ParseBuildErrors(lines []string) []BuildDiagnostic {
+	var diagnostics []BuildDiagnostic
+	index := make(map[string]int) // "file:line" -> index into diagnostics
+	current := -1
+
+	for _, line := range lines {
+		if packageBannerRe.MatchString(line) {
+			current = -1
+			continue
+		}
+
+		if m := buildDiagnosticRe.FindStringSubmatch(line); m != nil {
+			key := m[1] + ":" + m[2]
+			if i, ok := index[key]; ok {
+				diagnostics[i].Message += "\n" + m[4]
+				current = i
+				continue
+			}
+
+			lineNo, _ := strconv.Atoi(m[2])
+			col, _ := strconv.Atoi(m[3])
+			diagnostics = append(diagnostics, BuildDiagnostic{
+				File:    m[1],
+				Line:    lineNo,
+				Col:     col,
+				Message: m[4],
+			})
+			index[key] = len(diagnostics) - 1
+			current = len(diagnostics) - 1
+			continue
+		}
+
+		if current >= 0 && (strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "    ")) {
+			diagnostics[current].Message += "\n" + strings.TrimSpace(line)
+		}
+	}
+
+	return diagnostics
+}
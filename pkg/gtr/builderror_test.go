@@ -0,0 +1,68 @@
+package gtr
+
+import "testing"
+
+func TestParseBuildErrors_SingleDiagnostic(t *testing.T) {
+	lines := []string{
+		"# example.com/pkg",
+		"./foo.go:12:6: undefined: bar",
+	}
+
+	got := ParseBuildErrors(lines)
+	if len(got) != 1 {
+		t.Fatalf("got %d diagnostics, want 1: %+v", len(got), got)
+	}
+	d := got[0]
+	if d.File != "./foo.go" || d.Line != 12 || d.Col != 6 || d.Message != "undefined: bar" {
+		t.Errorf("diagnostic = %+v, want {./foo.go 12 6 undefined: bar}", d)
+	}
+}
+
+func TestParseBuildErrors_MultiLineContinuation(t *testing.T) {
+	lines := []string{
+		"./foo.go:12:6: undefined: bar",
+		"\tmore detail about bar",
+	}
+
+	got := ParseBuildErrors(lines)
+	if len(got) != 1 {
+		t.Fatalf("got %d diagnostics, want 1: %+v", len(got), got)
+	}
+	want := "undefined: bar\nmore detail about bar"
+	if got[0].Message != want {
+		t.Errorf("Message = %q, want %q", got[0].Message, want)
+	}
+}
+
+func TestParseBuildErrors_SeparatesPackages(t *testing.T) {
+	lines := []string{
+		"# pkg/a",
+		"./a.go:1:1: oops in a",
+		"# pkg/b",
+		"./b.go:2:2: oops in b",
+	}
+
+	got := ParseBuildErrors(lines)
+	if len(got) != 2 {
+		t.Fatalf("got %d diagnostics, want 2: %+v", len(got), got)
+	}
+	if got[0].File != "./a.go" || got[1].File != "./b.go" {
+		t.Errorf("diagnostics = %+v, want one per package", got)
+	}
+}
+
+func TestParseBuildErrors_DedupesSameSite(t *testing.T) {
+	lines := []string{
+		"./foo.go:5:1: first message",
+		"./foo.go:5:1: second message",
+	}
+
+	got := ParseBuildErrors(lines)
+	if len(got) != 1 {
+		t.Fatalf("got %d diagnostics, want 1 (same file:line should merge): %+v", len(got), got)
+	}
+	want := "first message\nsecond message"
+	if got[0].Message != want {
+		t.Errorf("Message = %q, want %q", got[0].Message, want)
+	}
+}
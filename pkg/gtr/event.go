@@ -0,0 +1,25 @@
+package gtr
+
+import "time"
+
+// Event represents a single event in a test or benchmark's lifecycle, as
+// produced by a parser and consumed by FromEvents to build a Report.
+type Event struct {
+	Type string
+
+	Name     string
+	Result   Result
+	Duration time.Duration
+	Indent   int
+
+	Data string
+
+	Iterations  int64
+	NsPerOp     float64
+	MBPerSec    float64
+	BytesPerOp  int64
+	AllocsPerOp int64
+
+	CovPct      float64
+	CovPackages string
+}
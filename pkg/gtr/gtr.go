@@ -47,11 +47,18 @@ type Package struct {
 }
 
 type Test struct {
+	ID       int
 	Name     string
 	Duration time.Duration
 	Result   Result
 	Level    int
 	Output   []string
+
+	// Attempts holds the result of every rerun attempt for this test, in
+	// order, when it was rerun because it initially failed. Flaky is true
+	// if at least one attempt passed and at least one failed.
+	Attempts []Result
+	Flaky    bool
 }
 
 type Benchmark struct {
@@ -63,6 +70,21 @@ type Benchmark struct {
 	MBPerSec    float64
 	BytesPerOp  int64
 	AllocsPerOp int64
+
+	// Samples holds every individual run of this benchmark, so statistics
+	// like standard deviation and min/max can be computed across runs
+	// instead of only keeping their average.
+	Samples []BenchmarkSample
+}
+
+// BenchmarkSample is a single run of a benchmark, as reported by `go test
+// -bench`.
+type BenchmarkSample struct {
+	Iterations  int64
+	NsPerOp     float64
+	MBPerSec    float64
+	BytesPerOp  int64
+	AllocsPerOp int64
 }
 
 type Error struct {
@@ -111,75 +133,92 @@ func JUnit(report Report, hostname string, now time.Time) junit.Testsuites {
 
 	var suites junit.Testsuites
 	for _, pkg := range report.Packages {
-		var duration time.Duration
-		suite := junit.Testsuite{
-			Name:      pkg.Name,
-			Timestamp: timestamp,
-			Hostname:  hostname,
-		}
+		suites.AddSuite(junitSuite(pkg, hostname, timestamp))
+	}
+	return suites
+}
 
-		if len(pkg.Output) > 0 {
-			suite.SystemOut = &junit.Output{Data: formatOutput(pkg.Output, 0)}
-		}
+// junitSuite converts a single Package into a junit.Testsuite. It's shared
+// by JUnit, which renders a full Report in one pass, and StreamingBuilder,
+// which renders one package at a time as its summary event arrives.
+func junitSuite(pkg Package, hostname, timestamp string) junit.Testsuite {
+	var duration time.Duration
+	suite := junit.Testsuite{
+		Name:      pkg.Name,
+		Timestamp: timestamp,
+		Hostname:  hostname,
+	}
 
-		if pkg.Coverage > 0 {
-			suite.AddProperty("coverage.statements.pct", fmt.Sprintf("%.2f", pkg.Coverage))
-		}
+	if len(pkg.Output) > 0 {
+		suite.SystemOut = &junit.Output{Data: formatOutput(pkg.Output, 0)}
+	}
 
-		for _, line := range pkg.Output {
-			if fields := strings.FieldsFunc(line, propFieldsFunc); len(fields) == 2 && propPrefixes[fields[0]] {
-				suite.AddProperty(fields[0], fields[1])
-			}
+	if pkg.Coverage > 0 {
+		suite.AddProperty("coverage.statements.pct", fmt.Sprintf("%.2f", pkg.Coverage))
+	}
+
+	for _, line := range pkg.Output {
+		if fields := strings.FieldsFunc(line, propFieldsFunc); len(fields) == 2 && propPrefixes[fields[0]] {
+			suite.AddProperty(fields[0], fields[1])
 		}
+	}
 
-		for _, test := range pkg.Tests {
-			duration += test.Duration
+	for _, test := range pkg.Tests {
+		duration += test.Duration
 
-			tc := junit.Testcase{
-				Classname: pkg.Name,
-				Name:      test.Name,
-				Time:      junit.FormatDuration(test.Duration),
-			}
+		tc := junit.Testcase{
+			Classname: pkg.Name,
+			Name:      test.Name,
+			Time:      junit.FormatDuration(test.Duration),
+		}
 
-			if test.Result == Fail {
-				tc.Failure = &junit.Result{
-					Message: "Failed",
-					Data:    formatOutput(test.Output, test.Level),
-				}
-			} else if test.Result == Skip {
-				tc.Skipped = &junit.Result{
-					Message: formatOutput(test.Output, test.Level),
-				}
-			} else if test.Result == Unknown {
-				tc.Error = &junit.Result{
-					Message: "No test result found",
-					Data:    formatOutput(test.Output, test.Level),
-				}
+		if test.Flaky {
+			tc.SystemOut = &junit.Output{Data: flakySystemOut(test)}
+		} else if test.Result == Fail {
+			tc.Failure = &junit.Result{
+				Message: "Failed",
+				Data:    formatOutput(test.Output, test.Level),
+			}
+		} else if test.Result == Skip {
+			tc.Skipped = &junit.Result{
+				Message: formatOutput(test.Output, test.Level),
+			}
+		} else if test.Result == Unknown {
+			tc.Error = &junit.Result{
+				Message: "No test result found",
+				Data:    formatOutput(test.Output, test.Level),
 			}
+		}
 
-			suite.AddTestcase(tc)
+		suite.AddTestcase(tc)
+	}
+
+	for _, bm := range mergeBenchmarks(pkg.Benchmarks) {
+		tc := junit.Testcase{
+			Classname: pkg.Name,
+			Name:      bm.Name,
+			Time:      junit.FormatBenchmarkTime(time.Duration(bm.NsPerOp)),
 		}
 
-		for _, bm := range mergeBenchmarks(pkg.Benchmarks) {
-			tc := junit.Testcase{
-				Classname: pkg.Name,
-				Name:      bm.Name,
-				Time:      junit.FormatBenchmarkTime(time.Duration(bm.NsPerOp)),
-			}
+		addBenchmarkProperties(&tc, bm)
 
-			if bm.Result == Fail {
-				tc.Failure = &junit.Result{
-					Message: "Failed",
-				}
+		if bm.Result == Fail {
+			tc.Failure = &junit.Result{
+				Message: "Failed",
 			}
-
-			suite.AddTestcase(tc)
 		}
 
-		// JUnit doesn't have a good way of dealing with build or runtime
-		// errors that happen before a test has started, so we create a single
-		// failing test that contains the build error details.
-		if pkg.BuildError.Name != "" {
+		suite.AddTestcase(tc)
+	}
+
+	// JUnit doesn't have a good way of dealing with build or runtime
+	// errors that happen before a test has started, so we create one
+	// failing testcase per broken file:line site we can identify in the
+	// build output, falling back to a single synthetic testcase covering
+	// the whole error when none of its lines match a diagnostic.
+	if pkg.BuildError.Name != "" {
+		diagnostics := ParseBuildErrors(pkg.BuildError.Output)
+		if len(diagnostics) == 0 {
 			tc := junit.Testcase{
 				Classname: pkg.BuildError.Name,
 				Name:      pkg.BuildError.Cause,
@@ -192,27 +231,81 @@ func JUnit(report Report, hostname string, now time.Time) junit.Testsuites {
 			suite.AddTestcase(tc)
 		}
 
-		if pkg.RunError.Name != "" {
+		for _, d := range diagnostics {
 			tc := junit.Testcase{
-				Classname: pkg.RunError.Name,
-				Name:      "Failure",
+				Classname: pkg.BuildError.Name,
+				Name:      fmt.Sprintf("%s:%d", d.File, d.Line),
 				Time:      junit.FormatDuration(0),
 				Error: &junit.Result{
-					Message: "Run error",
-					Data:    strings.Join(pkg.RunError.Output, "\n"),
+					Message: "Build error",
+					Data:    d.Message,
 				},
 			}
 			suite.AddTestcase(tc)
 		}
+	}
 
-		if (pkg.Duration) == 0 {
-			suite.Time = junit.FormatDuration(duration)
-		} else {
-			suite.Time = junit.FormatDuration(pkg.Duration)
+	if pkg.RunError.Name != "" {
+		tc := junit.Testcase{
+			Classname: pkg.RunError.Name,
+			Name:      "Failure",
+			Time:      junit.FormatDuration(0),
+			Error: &junit.Result{
+				Message: "Run error",
+				Data:    strings.Join(pkg.RunError.Output, "\n"),
+			},
 		}
-		suites.AddSuite(suite)
+		suite.AddTestcase(tc)
 	}
-	return suites
+
+	if (pkg.Duration) == 0 {
+		suite.Time = junit.FormatDuration(duration)
+	} else {
+		suite.Time = junit.FormatDuration(pkg.Duration)
+	}
+
+	return suite
+}
+
+// addBenchmarkProperties attaches mean/stddev/min/max statistics for each
+// of bm's metrics to tc as JUnit properties, so benchstat-style comparisons
+// can be done downstream without re-parsing raw benchmark output.
+func addBenchmarkProperties(tc *junit.Testcase, bm Benchmark) {
+	if len(bm.Samples) == 0 {
+		return
+	}
+
+	metrics := bm.Stats()
+	addStatsProperties(tc, "ns_per_op", metrics.NsPerOp)
+	addStatsProperties(tc, "mb_per_sec", metrics.MBPerSec)
+	addStatsProperties(tc, "bytes_per_op", metrics.BytesPerOp)
+	addStatsProperties(tc, "allocs_per_op", metrics.AllocsPerOp)
+}
+
+func addStatsProperties(tc *junit.Testcase, metric string, stats BenchmarkStats) {
+	tc.AddProperty(metric+".mean", fmt.Sprintf("%g", stats.Mean))
+	tc.AddProperty(metric+".stddev", fmt.Sprintf("%g", stats.Stddev))
+	tc.AddProperty(metric+".min", fmt.Sprintf("%g", stats.Min))
+	tc.AddProperty(metric+".max", fmt.Sprintf("%g", stats.Max))
+}
+
+// flakySystemOut builds the <system-out> body for a flaky test: the attempt
+// on which it finally passed, followed by the output of the original failing
+// run. Output of failed rerun attempts is not included.
+func flakySystemOut(test Test) string {
+	passedAttempt := len(test.Attempts)
+	for i, result := range test.Attempts {
+		if result == Pass {
+			passedAttempt = i + 1
+			break
+		}
+	}
+
+	lines := []string{fmt.Sprintf("flaky: passed on attempt %d", passedAttempt)}
+	if len(test.Output) > 0 {
+		lines = append(lines, formatOutput(test.Output, test.Level))
+	}
+	return strings.Join(lines, "\n")
 }
 
 func formatOutput(output []string, level int) string {
@@ -251,11 +344,22 @@ func mergeBenchmarks(benchmarks []Benchmark) []Benchmark {
 	}
 
 	for i, bm := range merged {
+		merged[i].Result = Pass
 		for _, b := range benchmap[bm.Name] {
 			bm.NsPerOp += b.NsPerOp
 			bm.MBPerSec += b.MBPerSec
 			bm.BytesPerOp += b.BytesPerOp
 			bm.AllocsPerOp += b.AllocsPerOp
+			if b.Result == Fail {
+				merged[i].Result = Fail
+			}
+			merged[i].Samples = append(merged[i].Samples, BenchmarkSample{
+				Iterations:  b.Iterations,
+				NsPerOp:     b.NsPerOp,
+				MBPerSec:    b.MBPerSec,
+				BytesPerOp:  b.BytesPerOp,
+				AllocsPerOp: b.AllocsPerOp,
+			})
 		}
 		n := len(benchmap[bm.Name])
 		merged[i].NsPerOp = bm.NsPerOp / float64(n)
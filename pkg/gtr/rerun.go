@@ -0,0 +1,69 @@
+package gtr
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TestRunner runs a single test identified by its slash-delimited name
+// (e.g. "TestA/sub_B") and returns the events produced by parsing its
+// output, so a rerun's results can be folded into the same Report rather
+// than parsed independently.
+type TestRunner func(testName string) ([]Event, error)
+
+// RerunFailed reruns every failed test in report up to maxAttempts times
+// using run, recording each attempt in Test.Attempts and marking the test
+// Flaky if at least one attempt passed.
+func RerunFailed(report Report, maxAttempts int, run TestRunner) Report {
+	for pi := range report.Packages {
+		tests := report.Packages[pi].Tests
+		for ti := range tests {
+			t := &tests[ti]
+			if t.Result != Fail {
+				continue
+			}
+			t.Attempts = append(t.Attempts, t.Result)
+
+			for attempt := 0; attempt < maxAttempts; attempt++ {
+				events, err := run(t.Name)
+				if err != nil {
+					break
+				}
+
+				result := lastResult(events, t.Name)
+				t.Attempts = append(t.Attempts, result)
+				if result == Pass {
+					t.Flaky = true
+					t.Result = Pass
+					break
+				}
+			}
+		}
+	}
+	return report
+}
+
+// lastResult returns the result of the "end_test" event for testName, or
+// Unknown if run's events don't contain one.
+func lastResult(events []Event, testName string) Result {
+	result := Unknown
+	for _, ev := range events {
+		if ev.Type == "end_test" && ev.Name == testName {
+			result = ev.Result
+		}
+	}
+	return result
+}
+
+// TestRunArg builds the `go test -run` regexp for a single, possibly
+// nested, test name the way gotestsum does: each "/"-delimited path
+// segment is quoted and anchored independently, so "A/B" becomes
+// "^A$/^B$" rather than a single anchored regexp that would also match
+// unrelated tests sharing a prefix.
+func TestRunArg(name string) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		parts[i] = "^" + regexp.QuoteMeta(part) + "$"
+	}
+	return strings.Join(parts, "/")
+}
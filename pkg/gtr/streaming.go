@@ -0,0 +1,149 @@
+package gtr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jstemmer/go-junit-report/v2/pkg/junit"
+)
+
+// StreamingBuilder incrementally builds and emits a JUnit report one
+// package at a time, instead of waiting for FromEvents to consume the
+// entire event stream first. It shares its per-test bookkeeping with
+// ReportBuilder, but finalizes a <testsuite> and flushes the report to
+// disk as soon as a package's summary event arrives, so CI systems can
+// consume partial results if the process is killed mid-run.
+type StreamingBuilder struct {
+	tee      io.Writer
+	progress io.Writer
+	path     string
+
+	builder  *ReportBuilder
+	hostname string
+	suites   junit.Testsuites
+}
+
+// NewStreamingBuilder creates a StreamingBuilder that tees raw "output"
+// event data to tee, the -iocopy behavior some downstream Makefiles rely
+// on. By default progress is written to os.Stderr and the incremental
+// JUnit XML is written to "report.xml"; use SetProgressWriter and
+// SetOutputPath to change either.
+func NewStreamingBuilder(tee io.Writer) *StreamingBuilder {
+	hostname, _ := os.Hostname()
+	return &StreamingBuilder{
+		tee:      tee,
+		progress: os.Stderr,
+		path:     "report.xml",
+		builder:  NewReportBuilder(""),
+		hostname: hostname,
+	}
+}
+
+// SetProgressWriter sets the writer that receives a compact progress line
+// for each completed package.
+func (s *StreamingBuilder) SetProgressWriter(w io.Writer) {
+	s.progress = w
+}
+
+// SetOutputPath sets the path the incremental JUnit XML file is written
+// to.
+func (s *StreamingBuilder) SetOutputPath(path string) {
+	s.path = path
+}
+
+// Feed processes a single event: it tees raw test output to the
+// StreamingBuilder's tee writer, and once a package's summary event
+// arrives, finalizes that package's <testsuite>, prints its progress
+// summary, and flushes the report so far to disk.
+func (s *StreamingBuilder) Feed(ev Event) error {
+	if ev.Type == "output" && s.tee != nil {
+		io.WriteString(s.tee, ev.Data)
+	}
+
+	switch ev.Type {
+	case "run_test":
+		s.builder.CreateTest(ev.Name)
+	case "pause_test":
+		s.builder.PauseTest(ev.Name)
+	case "cont_test":
+		s.builder.ContinueTest(ev.Name)
+	case "end_test":
+		s.builder.EndTest(ev.Name, ev.Result, ev.Duration, ev.Indent)
+	case "benchmark":
+		s.builder.Benchmark(ev.Name, ev.Iterations, ev.NsPerOp, ev.MBPerSec, ev.BytesPerOp, ev.AllocsPerOp)
+	case "status":
+		s.builder.End()
+	case "coverage":
+		s.builder.Coverage(ev.CovPct, ev.CovPackages)
+	case "build_output":
+		s.builder.CreateBuildError(ev.Name)
+	case "output":
+		s.builder.AppendOutput(ev.Data)
+	case "summary":
+		s.builder.CreatePackage(ev.Name, ev.Result, ev.Duration, ev.Data)
+		return s.finalizePackage()
+	default:
+		fmt.Fprintf(s.progress, "unhandled event type: %v\n", ev.Type)
+	}
+
+	return nil
+}
+
+// finalizePackage converts the most recently completed package into a
+// <testsuite>, reports its progress, and flushes the report built so far
+// to disk before the next package starts.
+func (s *StreamingBuilder) finalizePackage() error {
+	pkgs := s.builder.Build().Packages
+	pkg := pkgs[len(pkgs)-1]
+
+	s.logProgress(pkg)
+	s.suites.AddSuite(junitSuite(pkg, s.hostname, time.Now().Format(time.RFC3339)))
+
+	return s.flush()
+}
+
+// logProgress writes a single PASS/FAIL/SKIP line for pkg to the progress
+// writer.
+func (s *StreamingBuilder) logProgress(pkg Package) {
+	var pass, fail, skip int
+	for _, t := range pkg.Tests {
+		switch t.Result {
+		case Pass:
+			pass++
+		case Fail:
+			fail++
+		case Skip:
+			skip++
+		}
+	}
+
+	status := "ok"
+	if pkg.BuildError.Name != "" || pkg.RunError.Name != "" || fail > 0 {
+		status = "FAIL"
+	}
+
+	fmt.Fprintf(s.progress, "%s\t%s\t%.3fs\tpass=%d fail=%d skip=%d\n",
+		status, pkg.Name, pkg.Duration.Seconds(), pass, fail, skip)
+}
+
+// flush writes the report built so far to the configured output path,
+// overwriting the previous contents, so a kill mid-run still leaves valid
+// XML for every package that finished before it.
+func (s *StreamingBuilder) flush() error {
+	f, err := os.Create(s.path)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	return s.suites.WriteXML(f)
+}
+
+// Close finalizes the StreamingBuilder. Since each package is already
+// flushed to disk as it completes, Close only needs to ensure the last
+// write succeeded.
+func (s *StreamingBuilder) Close() error {
+	return s.flush()
+}
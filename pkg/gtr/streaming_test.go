@@ -0,0 +1,75 @@
+package gtr
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamingBuilder_FeedWritesIncrementalXML(t *testing.T) {
+	dir := t.TempDir()
+	outPath := filepath.Join(dir, "report.xml")
+
+	var tee, progress bytes.Buffer
+	sb := NewStreamingBuilder(&tee)
+	sb.SetProgressWriter(&progress)
+	sb.SetOutputPath(outPath)
+
+	events := []Event{
+		{Type: "run_test", Name: "TestFoo"},
+		{Type: "output", Name: "TestFoo", Data: "hello\n"},
+		{Type: "end_test", Name: "TestFoo", Result: Pass, Duration: time.Millisecond},
+		{Type: "summary", Name: "pkg", Result: Pass, Duration: time.Millisecond},
+	}
+
+	for _, ev := range events {
+		if err := sb.Feed(ev); err != nil {
+			t.Fatalf("Feed(%+v) error = %v", ev, err)
+		}
+	}
+	if err := sb.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if !strings.Contains(tee.String(), "hello") {
+		t.Errorf("tee writer = %q, want it to contain teed output", tee.String())
+	}
+	if !strings.Contains(progress.String(), "ok\tpkg") {
+		t.Errorf("progress writer = %q, want an ok progress line for pkg", progress.String())
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("reading flushed report: %v", err)
+	}
+	if !strings.Contains(string(data), `<testsuite name="pkg"`) {
+		t.Errorf("flushed XML = %q, want a testsuite for pkg", string(data))
+	}
+}
+
+func TestStreamingBuilder_FeedReportsFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	var progress bytes.Buffer
+	sb := NewStreamingBuilder(nil)
+	sb.SetProgressWriter(&progress)
+	sb.SetOutputPath(filepath.Join(dir, "report.xml"))
+
+	events := []Event{
+		{Type: "run_test", Name: "TestBar"},
+		{Type: "end_test", Name: "TestBar", Result: Fail},
+		{Type: "summary", Name: "pkg", Result: Fail},
+	}
+	for _, ev := range events {
+		if err := sb.Feed(ev); err != nil {
+			t.Fatalf("Feed(%+v) error = %v", ev, err)
+		}
+	}
+
+	if !strings.Contains(progress.String(), "FAIL\tpkg") {
+		t.Errorf("progress writer = %q, want a FAIL progress line for pkg", progress.String())
+	}
+}
@@ -0,0 +1,133 @@
+// Package junit defines data structures for representing test results as
+// JUnit XML, and provides helpers for formatting durations the way JUnit
+// consumers expect.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Testsuites is a collection of JUnit testsuites.
+type Testsuites struct {
+	XMLName xml.Name `xml:"testsuites"`
+
+	Suites []Testsuite `xml:"testsuite"`
+}
+
+// AddSuite adds a Testsuite to this collection.
+func (t *Testsuites) AddSuite(suite Testsuite) {
+	t.Suites = append(t.Suites, suite)
+}
+
+// WriteXML marshals the testsuites as XML and writes them to w, preceded by
+// the standard XML header.
+func (t Testsuites) WriteXML(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	return enc.Encode(t)
+}
+
+// Testsuite models a JUnit testsuite, normally corresponding to a single Go
+// package.
+type Testsuite struct {
+	XMLName xml.Name `xml:"testsuite"`
+
+	Name      string `xml:"name,attr"`
+	Tests     int    `xml:"tests,attr"`
+	Failures  int    `xml:"failures,attr"`
+	Errors    int    `xml:"errors,attr"`
+	Skipped   int    `xml:"skipped,attr"`
+	Time      string `xml:"time,attr"`
+	Timestamp string `xml:"timestamp,attr,omitempty"`
+	Hostname  string `xml:"hostname,attr,omitempty"`
+
+	Properties []Property  `xml:"properties>property,omitempty"`
+	Testcases  []Testcase  `xml:"testcase"`
+	Suites     []Testsuite `xml:"testsuite,omitempty"`
+	SystemOut  *Output     `xml:"system-out,omitempty"`
+	SystemErr  *Output     `xml:"system-err,omitempty"`
+}
+
+// AddSuite adds a nested child testsuite to this testsuite, as used by the
+// Ginkgo-style report format to represent a subtest tree.
+func (t *Testsuite) AddSuite(suite Testsuite) {
+	t.Suites = append(t.Suites, suite)
+}
+
+// AddProperty adds a name/value property to this testsuite.
+func (t *Testsuite) AddProperty(name, value string) {
+	t.Properties = append(t.Properties, Property{Name: name, Value: value})
+}
+
+// AddTestcase adds a testcase to this testsuite, updating the aggregate
+// counters accordingly.
+func (t *Testsuite) AddTestcase(tc Testcase) {
+	t.Tests++
+	if tc.Failure != nil {
+		t.Failures++
+	}
+	if tc.Error != nil {
+		t.Errors++
+	}
+	if tc.Skipped != nil {
+		t.Skipped++
+	}
+	t.Testcases = append(t.Testcases, tc)
+}
+
+// Property is a single name/value pair attached to a testsuite.
+type Property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// Testcase models a single JUnit testcase, normally corresponding to a Go
+// test or benchmark.
+type Testcase struct {
+	XMLName   xml.Name `xml:"testcase"`
+	Classname string   `xml:"classname,attr"`
+	Name      string   `xml:"name,attr"`
+	Time      string   `xml:"time,attr"`
+
+	Properties []Property `xml:"properties>property,omitempty"`
+	Failure    *Result    `xml:"failure,omitempty"`
+	Error      *Result    `xml:"error,omitempty"`
+	Skipped    *Result    `xml:"skipped,omitempty"`
+	SystemOut  *Output    `xml:"system-out,omitempty"`
+}
+
+// AddProperty adds a name/value property to this testcase.
+func (tc *Testcase) AddProperty(name, value string) {
+	tc.Properties = append(tc.Properties, Property{Name: name, Value: value})
+}
+
+// Result holds the message and output data for a failed, errored, or
+// skipped testcase.
+type Result struct {
+	Message string `xml:"message,attr,omitempty"`
+	Data    string `xml:",chardata"`
+}
+
+// Output wraps free-form text output, such as a testsuite's combined
+// stdout.
+type Output struct {
+	Data string `xml:",cdata"`
+}
+
+// FormatDuration formats d the way JUnit expects durations: seconds with
+// millisecond precision.
+func FormatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// FormatBenchmarkTime formats d the way JUnit expects a benchmark's
+// per-operation time: seconds with nanosecond precision.
+func FormatBenchmarkTime(d time.Duration) string {
+	return fmt.Sprintf("%.9f", d.Seconds())
+}
@@ -0,0 +1,153 @@
+// Package merge combines multiple JUnit report files into a single
+// aggregated junit.Testsuites, the way CI systems that shard `go test`
+// across workers need to reassemble results afterwards.
+package merge
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/jstemmer/go-junit-report/v2/pkg/junit"
+)
+
+// Files reads and merges the JUnit XML reports at the given paths into a
+// single junit.Testsuites, deduplicating testsuites by name and retried
+// testcases by classname+name.
+func Files(paths []string) (junit.Testsuites, error) {
+	var merged junit.Testsuites
+
+	suitesByName := make(map[string]*junit.Testsuite)
+	var order []string
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return merged, fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		var suites junit.Testsuites
+		if err := xml.Unmarshal(data, &suites); err != nil {
+			return merged, fmt.Errorf("parsing %s: %w", path, err)
+		}
+
+		for _, suite := range suites.Suites {
+			existing, ok := suitesByName[suite.Name]
+			if !ok {
+				s := suite
+				s.Testcases = nil
+				s.Time = ""
+				existing = &s
+				suitesByName[suite.Name] = existing
+				order = append(order, suite.Name)
+			}
+			mergeSuite(existing, suite)
+		}
+	}
+
+	for _, name := range order {
+		suite := *suitesByName[name]
+		recomputeCounts(&suite)
+		merged.AddSuite(suite)
+	}
+
+	return merged, nil
+}
+
+// mergeSuite folds src's testcases and total time into dst, collapsing
+// retried testcases of the same classname+name into a single case whose
+// final result reflects the last attempt. dst's Tests/Failures/Errors/
+// Skipped counters are left stale here — they're recomputed from the final
+// testcase set once every file has been merged, since summing per-file
+// counts directly would double-count any testcase that got collapsed as a
+// retry.
+func mergeSuite(dst *junit.Testsuite, src junit.Testsuite) {
+	dst.Time = addDurations(dst.Time, src.Time)
+
+	byKey := make(map[string]int) // classname+name -> index into dst.Testcases
+	for i, tc := range dst.Testcases {
+		byKey[testcaseKey(tc)] = i
+	}
+
+	for _, tc := range src.Testcases {
+		key := testcaseKey(tc)
+		i, ok := byKey[key]
+		if !ok {
+			byKey[key] = len(dst.Testcases)
+			dst.Testcases = append(dst.Testcases, tc)
+			continue
+		}
+		dst.Testcases[i] = mergeRetry(dst.Testcases[i], tc)
+	}
+}
+
+// recomputeCounts sets suite's Tests/Failures/Errors/Skipped from its final
+// Testcases, so the counters always match what's actually in the file
+// regardless of how many retried attempts were collapsed into each case.
+func recomputeCounts(suite *junit.Testsuite) {
+	suite.Tests = len(suite.Testcases)
+	suite.Failures = 0
+	suite.Errors = 0
+	suite.Skipped = 0
+	for _, tc := range suite.Testcases {
+		if tc.Failure != nil {
+			suite.Failures++
+		}
+		if tc.Error != nil {
+			suite.Errors++
+		}
+		if tc.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+}
+
+// mergeRetry combines two attempts of the same testcase, keeping the final
+// attempt's result and recording the earlier attempt's outcome — including
+// its failure or error output — as system-out history, regardless of
+// whether the final attempt passed, so a flaky test's prior failure isn't
+// silently discarded once it goes green.
+func mergeRetry(prev, next junit.Testcase) junit.Testcase {
+	history := fmt.Sprintf("attempt result: %s", attemptResult(prev))
+	switch {
+	case prev.Failure != nil && prev.Failure.Data != "":
+		history += "\n" + prev.Failure.Data
+	case prev.Error != nil && prev.Error.Data != "":
+		history += "\n" + prev.Error.Data
+	}
+	if prev.SystemOut != nil && prev.SystemOut.Data != "" {
+		history += "\n" + prev.SystemOut.Data
+	}
+
+	merged := next
+	if merged.SystemOut != nil && merged.SystemOut.Data != "" {
+		history = merged.SystemOut.Data + "\n" + history
+	}
+	merged.SystemOut = &junit.Output{Data: history}
+
+	return merged
+}
+
+func attemptResult(tc junit.Testcase) string {
+	switch {
+	case tc.Failure != nil:
+		return "failed"
+	case tc.Error != nil:
+		return "errored"
+	case tc.Skipped != nil:
+		return "skipped"
+	default:
+		return "passed"
+	}
+}
+
+func testcaseKey(tc junit.Testcase) string {
+	return tc.Classname + "\x00" + tc.Name
+}
+
+func addDurations(a, b string) string {
+	af, _ := strconv.ParseFloat(a, 64)
+	bf, _ := strconv.ParseFloat(b, 64)
+	return strconv.FormatFloat(af+bf, 'f', 3, 64)
+}
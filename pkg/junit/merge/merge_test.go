@@ -0,0 +1,71 @@
+package merge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const shard1XML = `<testsuites>
+  <testsuite name="pkg" tests="2" failures="1" errors="0" skipped="0" time="1.000">
+    <testcase classname="pkg" name="TestFlaky" time="0.500">
+      <failure message="Failed">boom</failure>
+    </testcase>
+    <testcase classname="pkg" name="TestOther" time="0.500"></testcase>
+  </testsuite>
+</testsuites>`
+
+const shard2XML = `<testsuites>
+  <testsuite name="pkg" tests="1" failures="0" errors="0" skipped="0" time="0.500">
+    <testcase classname="pkg" name="TestFlaky" time="0.400"></testcase>
+  </testsuite>
+</testsuites>`
+
+func TestFiles_RetryEndsPassing(t *testing.T) {
+	dir := t.TempDir()
+	f1 := writeFile(t, dir, "shard1.xml", shard1XML)
+	f2 := writeFile(t, dir, "shard2.xml", shard2XML)
+
+	suites, err := Files([]string{f1, f2})
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+	if len(suites.Suites) != 1 {
+		t.Fatalf("got %d suites, want 1", len(suites.Suites))
+	}
+
+	suite := suites.Suites[0]
+	if suite.Tests != 2 {
+		t.Errorf("Tests = %d, want 2 (retries collapse into one case, not double-counted)", suite.Tests)
+	}
+	if suite.Failures != 0 {
+		t.Errorf("Failures = %d, want 0 (final attempt passed)", suite.Failures)
+	}
+
+	found := false
+	for _, tc := range suite.Testcases {
+		if tc.Name != "TestFlaky" {
+			continue
+		}
+		found = true
+		if tc.Failure != nil {
+			t.Errorf("TestFlaky still has a Failure after a passing retry")
+		}
+		if tc.SystemOut == nil || !strings.Contains(tc.SystemOut.Data, "boom") {
+			t.Errorf("TestFlaky.SystemOut = %v, want prior failure output preserved", tc.SystemOut)
+		}
+	}
+	if !found {
+		t.Fatal("TestFlaky testcase not found in merged suite")
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	return path
+}
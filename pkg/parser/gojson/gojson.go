@@ -0,0 +1,114 @@
+// Package gojson parses the newline-delimited JSON test events produced by
+// `go test -json` (Go's cmd/test2json) into the internal gtr.Event stream
+// the rest of this tool's pipeline consumes.
+package gojson
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jstemmer/go-junit-report/v2/pkg/gtr"
+)
+
+// testEvent mirrors the JSON object cmd/test2json emits for each line of
+// `go test -json` output.
+type testEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64 // seconds
+	Output  string
+}
+
+// Parse reads newline-delimited test2json records from r and converts them
+// into the gtr.Event stream that gtr.FromEvents and gtr.StreamingBuilder
+// expect, so a rerun's output can be folded into the same Report instead
+// of being parsed by a separate, incompatible code path.
+func Parse(r io.Reader) ([]gtr.Event, error) {
+	var events []gtr.Event
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var buildOutput []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		var te testEvent
+		if err := json.Unmarshal([]byte(line), &te); err != nil {
+			// Build failures are reported as plain text rather than JSON,
+			// even under -json, since the test binary never started.
+			buildOutput = append(buildOutput, line)
+			continue
+		}
+
+		ev, ok := convert(te)
+		if ok {
+			events = append(events, ev)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(buildOutput) > 0 {
+		for _, line := range buildOutput {
+			events = append(events, gtr.Event{Type: "output", Data: line})
+		}
+		events = append(events, gtr.Event{Type: "build_output", Name: "build failed"})
+	}
+
+	return events, nil
+}
+
+// convert maps a single test2json record onto the corresponding gtr.Event,
+// or returns ok=false for records this tool doesn't act on (e.g. "start").
+func convert(te testEvent) (gtr.Event, bool) {
+	switch te.Action {
+	case "run":
+		return gtr.Event{Type: "run_test", Name: te.Test}, true
+	case "pause":
+		return gtr.Event{Type: "pause_test", Name: te.Test}, true
+	case "cont":
+		return gtr.Event{Type: "cont_test", Name: te.Test}, true
+	case "output":
+		return gtr.Event{Type: "output", Name: te.Test, Data: strings.TrimSuffix(te.Output, "\n")}, true
+	case "pass", "fail", "skip":
+		if te.Test == "" {
+			return gtr.Event{
+				Type:     "summary",
+				Name:     te.Package,
+				Result:   resultFor(te.Action),
+				Duration: time.Duration(te.Elapsed * float64(time.Second)),
+			}, true
+		}
+		return gtr.Event{
+			Type:     "end_test",
+			Name:     te.Test,
+			Result:   resultFor(te.Action),
+			Duration: time.Duration(te.Elapsed * float64(time.Second)),
+			Indent:   strings.Count(te.Test, "/"),
+		}, true
+	default:
+		return gtr.Event{}, false
+	}
+}
+
+func resultFor(action string) gtr.Result {
+	switch action {
+	case "pass":
+		return gtr.Pass
+	case "fail":
+		return gtr.Fail
+	case "skip":
+		return gtr.Skip
+	default:
+		return gtr.Unknown
+	}
+}
@@ -0,0 +1,58 @@
+package gojson
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jstemmer/go-junit-report/v2/pkg/gtr"
+)
+
+func TestParse(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"run","Package":"pkg","Test":"TestFoo"}`,
+		`{"Action":"output","Package":"pkg","Test":"TestFoo","Output":"hello\n"}`,
+		`{"Action":"pass","Package":"pkg","Test":"TestFoo","Elapsed":0.01}`,
+		`{"Action":"pass","Package":"pkg","Elapsed":0.02}`,
+	}, "\n")
+
+	events, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []gtr.Event{
+		{Type: "run_test", Name: "TestFoo"},
+		{Type: "output", Name: "TestFoo", Data: "hello"},
+		{Type: "end_test", Name: "TestFoo", Result: gtr.Pass},
+		{Type: "summary", Name: "pkg", Result: gtr.Pass},
+	}
+
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i := range want {
+		if events[i].Type != want[i].Type || events[i].Name != want[i].Name ||
+			events[i].Data != want[i].Data || events[i].Result != want[i].Result {
+			t.Errorf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
+func TestParse_BuildFailure(t *testing.T) {
+	input := "# pkg\n./foo.go:3:2: undefined: bar\nFAIL\tpkg [build failed]\n"
+
+	events, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var sawBuildOutput bool
+	for _, ev := range events {
+		if ev.Type == "build_output" {
+			sawBuildOutput = true
+		}
+	}
+	if !sawBuildOutput {
+		t.Errorf("Parse() of non-JSON build failure output did not emit a build_output event: %+v", events)
+	}
+}
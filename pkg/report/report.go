@@ -0,0 +1,249 @@
+// Package report converts a gtr.Report into one of several on-disk report
+// formats.
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/jstemmer/go-junit-report/v2/pkg/gtr"
+	"github.com/jstemmer/go-junit-report/v2/pkg/junit"
+)
+
+// Format identifies one of the supported report output formats.
+type Format string
+
+const (
+	FormatJUnit  Format = "junit"
+	FormatJSON   Format = "json"
+	FormatBazel  Format = "bazel"
+	FormatGinkgo Format = "ginkgo"
+)
+
+// Writer renders a gtr.Report to w in a specific output format.
+type Writer interface {
+	Write(w io.Writer, report gtr.Report) error
+}
+
+// NewWriter returns the Writer for the given format, or false if format is
+// not recognized.
+func NewWriter(format Format, hostname string, now time.Time) (Writer, bool) {
+	switch format {
+	case FormatJUnit, "":
+		return &JUnitWriter{Hostname: hostname, Now: now}, true
+	case FormatJSON:
+		return &JSONWriter{}, true
+	case FormatBazel:
+		return &BazelWriter{Hostname: hostname, Now: now}, true
+	case FormatGinkgo:
+		return &GinkgoWriter{Hostname: hostname, Now: now}, true
+	default:
+		return nil, false
+	}
+}
+
+// JUnitWriter renders a report as the standard JUnit XML dialect produced
+// by gtr.JUnit.
+type JUnitWriter struct {
+	Hostname string
+	Now      time.Time
+}
+
+func (jw *JUnitWriter) Write(w io.Writer, report gtr.Report) error {
+	suites := gtr.JUnit(report, jw.Hostname, jw.Now)
+	return suites.WriteXML(w)
+}
+
+// JSONWriter renders a report as machine-readable JSON, suitable for
+// downstream aggregation without going through JUnit XML at all.
+type JSONWriter struct{}
+
+func (JSONWriter) Write(w io.Writer, report gtr.Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// BazelWriter renders a report as a Bazel-compatible test.xml: a single
+// <testsuite> per package with a <system-out> containing the package's full
+// output, as expected by Bazel's test result consumers.
+type BazelWriter struct {
+	Hostname string
+	Now      time.Time
+}
+
+func (bw *BazelWriter) Write(w io.Writer, report gtr.Report) error {
+	timestamp := bw.Now.Format(time.RFC3339)
+
+	var suites junit.Testsuites
+	for _, pkg := range report.Packages {
+		suite := junit.Testsuite{
+			Name:      pkg.Name,
+			Timestamp: timestamp,
+			Hostname:  bw.Hostname,
+			Time:      junit.FormatDuration(pkg.Duration),
+		}
+
+		if len(pkg.Output) > 0 {
+			suite.SystemOut = &junit.Output{Data: joinLines(pkg.Output)}
+		}
+
+		for _, test := range pkg.Tests {
+			tc := junit.Testcase{
+				Classname: pkg.Name,
+				Name:      test.Name,
+				Time:      junit.FormatDuration(test.Duration),
+			}
+			if test.Result == gtr.Fail {
+				tc.Failure = &junit.Result{Message: "Failed"}
+			} else if test.Result == gtr.Skip {
+				tc.Skipped = &junit.Result{}
+			}
+			suite.AddTestcase(tc)
+		}
+
+		suites.AddSuite(suite)
+	}
+
+	return suites.WriteXML(w)
+}
+
+// GinkgoWriter renders a report as a Ginkgo-style <testsuites> tree, where
+// a test that has subtests becomes a nested <testsuite> holding them, rather
+// than every test being a flat <testcase> of the package suite. Nesting
+// follows the "/"-delimited test names. Subtests without subtests of their
+// own stay <testcase> entries and keep their full slash-delimited names.
+type GinkgoWriter struct {
+	Hostname string
+	Now      time.Time
+}
+
+func (gw *GinkgoWriter) Write(w io.Writer, report gtr.Report) error {
+	timestamp := gw.Now.Format(time.RFC3339)
+
+	var suites junit.Testsuites
+	for _, pkg := range report.Packages {
+		suite := junit.Testsuite{
+			Name:      pkg.Name,
+			Timestamp: timestamp,
+			Hostname:  gw.Hostname,
+			Time:      junit.FormatDuration(pkg.Duration),
+		}
+
+		for _, root := range nestTests(pkg.Tests) {
+			addNestedTest(&suite, pkg.Name, root)
+		}
+
+		suites.AddSuite(suite)
+	}
+
+	return suites.WriteXML(w)
+}
+
+// nestedTest is a gtr.Test together with its direct subtests, grouped by
+// name prefix rather than Output bookkeeping.
+type nestedTest struct {
+	test     gtr.Test
+	children []*nestedTest
+}
+
+// nestTests groups a flat list of tests (as produced by gtr.FromEvents,
+// where subtests immediately follow their parent) into a tree based on
+// "/"-delimited test names.
+func nestTests(tests []gtr.Test) []*nestedTest {
+	var roots []*nestedTest
+	byName := make(map[string]*nestedTest)
+
+	for _, t := range tests {
+		node := &nestedTest{test: t}
+		byName[t.Name] = node
+
+		if parent := parentName(t.Name); parent != "" {
+			if p, ok := byName[parent]; ok {
+				p.children = append(p.children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return roots
+}
+
+func parentName(name string) string {
+	if i := lastSlash(name); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+func lastSlash(name string) int {
+	for i := len(name) - 1; i >= 0; i-- {
+		if name[i] == '/' {
+			return i
+		}
+	}
+	return -1
+}
+
+// addNestedTest adds node to suite. A test without children becomes a flat
+// <testcase>; a test with children becomes its own nested <testsuite> so
+// the hierarchy of a Ginkgo-style spec tree is preserved, instead of
+// collapsing into slash-delimited testcase names. It returns the number of
+// tests/failures/errors/skipped testcases in node's subtree, so a caller
+// nesting several levels deep can fold descendant counts into every
+// ancestor's aggregate attributes, not just the immediate parent: AddSuite
+// doesn't update its own suite's counters the way AddTestcase does.
+func addNestedTest(suite *junit.Testsuite, classname string, node *nestedTest) (tests, failures, errors, skipped int) {
+	if len(node.children) == 0 {
+		tc := junit.Testcase{
+			Classname: classname,
+			Name:      node.test.Name,
+			Time:      junit.FormatDuration(node.test.Duration),
+		}
+		if node.test.Result == gtr.Fail {
+			tc.Failure = &junit.Result{Message: "Failed", Data: joinLines(node.test.Output)}
+			failures = 1
+		} else if node.test.Result == gtr.Skip {
+			tc.Skipped = &junit.Result{Message: joinLines(node.test.Output)}
+			skipped = 1
+		}
+		suite.AddTestcase(tc)
+		return 1, failures, errors, skipped
+	}
+
+	child := junit.Testsuite{
+		Name: node.test.Name,
+		Time: junit.FormatDuration(node.test.Duration),
+	}
+	for _, c := range node.children {
+		ct, cf, ce, cs := addNestedTest(&child, classname, c)
+		tests += ct
+		failures += cf
+		errors += ce
+		skipped += cs
+	}
+	child.Tests = tests
+	child.Failures = failures
+	child.Errors = errors
+	child.Skipped = skipped
+	suite.AddSuite(child)
+
+	suite.Tests += tests
+	suite.Failures += failures
+	suite.Errors += errors
+	suite.Skipped += skipped
+	return tests, failures, errors, skipped
+}
+
+func joinLines(lines []string) string {
+	var out string
+	for i, line := range lines {
+		if i > 0 {
+			out += "\n"
+		}
+		out += line
+	}
+	return out
+}
@@ -0,0 +1,155 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jstemmer/go-junit-report/v2/pkg/gtr"
+)
+
+func TestNewWriter(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   bool
+	}{
+		{FormatJUnit, true},
+		{"", true},
+		{FormatJSON, true},
+		{FormatBazel, true},
+		{FormatGinkgo, true},
+		{"bogus", false},
+	}
+
+	for _, tt := range tests {
+		_, ok := NewWriter(tt.format, "host", time.Now())
+		if ok != tt.want {
+			t.Errorf("NewWriter(%q) ok = %v, want %v", tt.format, ok, tt.want)
+		}
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	report := gtr.Report{Packages: []gtr.Package{{Name: "pkg"}}}
+
+	var buf bytes.Buffer
+	if err := (JSONWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"Name": "pkg"`) {
+		t.Errorf("output = %q, want it to contain package name", buf.String())
+	}
+}
+
+func TestBazelWriter(t *testing.T) {
+	report := gtr.Report{
+		Packages: []gtr.Package{
+			{
+				Name:   "pkg",
+				Output: []string{"coverage: 80.0% of statements"},
+				Tests: []gtr.Test{
+					{Name: "TestPass", Result: gtr.Pass},
+					{Name: "TestFail", Result: gtr.Fail},
+				},
+			},
+		},
+	}
+
+	bw := &BazelWriter{Hostname: "host", Now: time.Unix(0, 0)}
+	var buf bytes.Buffer
+	if err := bw.Write(&buf, report); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `<testsuite name="pkg"`) {
+		t.Errorf("output missing testsuite for package: %s", out)
+	}
+	if !strings.Contains(out, `name="TestFail"`) || !strings.Contains(out, "<failure") {
+		t.Errorf("output missing failed testcase: %s", out)
+	}
+	if !strings.Contains(out, "coverage: 80.0%") {
+		t.Errorf("output missing package system-out: %s", out)
+	}
+}
+
+func TestGinkgoWriter_NestsSubtests(t *testing.T) {
+	report := gtr.Report{
+		Packages: []gtr.Package{
+			{
+				Name: "pkg",
+				Tests: []gtr.Test{
+					{Name: "TestParent", Result: gtr.Pass},
+					{Name: "TestParent/Child", Result: gtr.Fail},
+				},
+			},
+		},
+	}
+
+	gw := &GinkgoWriter{Hostname: "host", Now: time.Unix(0, 0)}
+	var buf bytes.Buffer
+	if err := gw.Write(&buf, report); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `<testsuite name="TestParent"`) {
+		t.Errorf("output missing nested testsuite for parent: %s", out)
+	}
+	if !strings.Contains(out, `name="TestParent/Child"`) {
+		t.Errorf("output missing child testcase name: %s", out)
+	}
+}
+
+// TestGinkgoWriter_PropagatesCountsUpAncestorChain reproduces a 3-level
+// spec tree (package > TestParent > TestParent/Mid > TestParent/Mid/Child)
+// with a failing leaf, and checks that every ancestor's aggregate
+// attributes reflect it, not just the immediate parent.
+func TestGinkgoWriter_PropagatesCountsUpAncestorChain(t *testing.T) {
+	report := gtr.Report{
+		Packages: []gtr.Package{
+			{
+				Name: "pkg",
+				Tests: []gtr.Test{
+					{Name: "TestParent", Result: gtr.Pass},
+					{Name: "TestParent/Mid", Result: gtr.Pass},
+					{Name: "TestParent/Mid/Child", Result: gtr.Fail},
+				},
+			},
+		},
+	}
+
+	gw := &GinkgoWriter{Hostname: "host", Now: time.Unix(0, 0)}
+	var buf bytes.Buffer
+	if err := gw.Write(&buf, report); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `<testsuite name="pkg" tests="1" failures="1"`) {
+		t.Errorf("root package suite counts not propagated: %s", out)
+	}
+	if !strings.Contains(out, `<testsuite name="TestParent" tests="1" failures="1"`) {
+		t.Errorf("TestParent suite counts not propagated: %s", out)
+	}
+	if !strings.Contains(out, `<testsuite name="TestParent/Mid" tests="1" failures="1"`) {
+		t.Errorf("TestParent/Mid suite counts not propagated: %s", out)
+	}
+}
+
+func TestParentName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"TestFoo", ""},
+		{"TestFoo/Bar", "TestFoo"},
+		{"TestFoo/Bar/Baz", "TestFoo/Bar"},
+	}
+	for _, tt := range tests {
+		if got := parentName(tt.name); got != tt.want {
+			t.Errorf("parentName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}